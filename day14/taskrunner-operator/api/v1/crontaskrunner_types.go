@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConcurrencyPolicy decides how to treat a firing while a previous
+// CronTaskRunner-created TaskRunner is still running
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	ConcurrencyPolicyAllow   ConcurrencyPolicy = "Allow"
+	ConcurrencyPolicyForbid  ConcurrencyPolicy = "Forbid"
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "Replace"
+)
+
+// CronTaskRunnerSpec defines the desired state of CronTaskRunner
+type CronTaskRunnerSpec struct {
+	// Schedule in cron format, e.g. "0 */2 * * *"
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+
+	// TimeZone the Schedule is evaluated in, e.g. "America/New_York".
+	// Defaults to UTC when unset.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+
+	// Suspend pauses future firings without affecting already-created
+	// TaskRunners
+	// +kubebuilder:default=false
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// ConcurrencyPolicy decides what happens when a firing is due while an
+	// earlier TaskRunner is still active
+	// +kubebuilder:default=Allow
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// StartingDeadlineSeconds bounds how late a missed firing may start
+	// before it is skipped, to avoid runaway backfill after controller
+	// downtime
+	// +kubebuilder:validation:Minimum=0
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// SuccessfulHistoryLimit caps the number of Succeeded TaskRunners kept,
+	// oldest deleted first
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	SuccessfulHistoryLimit *int32 `json:"successfulHistoryLimit,omitempty"`
+
+	// FailedHistoryLimit caps the number of Failed TaskRunners kept, oldest
+	// deleted first
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	FailedHistoryLimit *int32 `json:"failedHistoryLimit,omitempty"`
+
+	// JobTemplate is the TaskRunnerSpec used for each firing
+	// +kubebuilder:validation:Required
+	JobTemplate TaskRunnerSpec `json:"jobTemplate"`
+}
+
+// CronTaskRunnerStatus defines the observed state of CronTaskRunner
+type CronTaskRunnerStatus struct {
+	// LastScheduleTime is when the most recent firing was created
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// LastSuccessfulTime is the latest completion time among this
+	// CronTaskRunner's TaskRunners that reached Succeeded
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// Active references the TaskRunners created by this CronTaskRunner that
+	// have not yet reached a terminal phase
+	Active []corev1.ObjectReference `json:"active,omitempty"`
+
+	// Conditions represent the latest available observations
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// CronTaskRunner is the Schema for the crontaskrunners API
+type CronTaskRunner struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of CronTaskRunner
+	// +required
+	Spec CronTaskRunnerSpec `json:"spec"`
+
+	// status defines the observed state of CronTaskRunner
+	// +optional
+	Status CronTaskRunnerStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// CronTaskRunnerList contains a list of CronTaskRunner
+type CronTaskRunnerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CronTaskRunner `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CronTaskRunner{}, &CronTaskRunnerList{})
+}
@@ -17,27 +17,38 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // TaskRunnerSpec defines the desired state of TaskRunner
 type TaskRunnerSpec struct {
 	// Command to execute
-	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:MinItems=1
-	Command []string `json:"command"`
+	//
+	// Deprecated: set Templates["default"].Command instead. Command is
+	// still honored by synthesizing a "default" template when Templates is
+	// unset, to preserve single-job TaskRunners.
+	// +optional
+	Command []string `json:"command,omitempty"`
 
 	// Container image to run
-	// +kubebuilder:validation:Required
-	Image string `json:"image"`
+	//
+	// Deprecated: set Templates["default"].Image instead. Image is still
+	// honored by synthesizing a "default" template when Templates is unset.
+	// +optional
+	Image string `json:"image,omitempty"`
 
 	// Number of parallel executions
+	//
+	// Deprecated: set Templates["default"].Parallelism instead.
 	// +kubebuilder:default=1
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=100
 	Parallelism int32 `json:"parallelism,omitempty"`
 
 	// Completion deadline in seconds
+	//
+	// Deprecated: set Templates["default"].DeadlineSeconds instead.
 	// +kubebuilder:validation:Minimum=1
 	DeadlineSeconds *int64 `json:"deadlineSeconds,omitempty"`
 
@@ -45,6 +56,128 @@ type TaskRunnerSpec struct {
 	// +kubebuilder:default=Managed
 	// +kubebuilder:validation:Enum=Managed;Unmanaged;Removed
 	ManagementState string `json:"managementState,omitempty"`
+
+	// Hooks run additional one-off Jobs around the main Job's lifecycle
+	// +optional
+	Hooks *Hooks `json:"hooks,omitempty"`
+
+	// ManagedBy identifies the controller that reconciles this TaskRunner.
+	// When set to a value other than the reserved controller name, this
+	// controller does not create Jobs or add its finalizer, leaving the
+	// TaskRunner for an external controller (e.g. a Kueue-style queueing
+	// controller) to admit and run.
+	// +kubebuilder:default="batch.example.com/taskrunner-controller"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="managedBy is immutable"
+	// +optional
+	ManagedBy *string `json:"managedBy,omitempty"`
+
+	// Templates names the Job templates this TaskRunner may run. When unset,
+	// a "default" template is synthesized from the deprecated top-level
+	// Image/Command/Parallelism/DeadlineSeconds fields.
+	// +optional
+	Templates map[string]JobTemplate `json:"templates,omitempty"`
+
+	// Sequence lists Template names to run in order, one Job at a time. A
+	// single-entry Sequence (or the implicit ["default"] when Templates is
+	// unset) preserves single-job semantics.
+	// +optional
+	Sequence []string `json:"sequence,omitempty"`
+}
+
+// JobTemplate describes one Job to run as part of a TaskRunner's Sequence
+type JobTemplate struct {
+	// Container image to run
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// Command to execute
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+
+	// Args are appended to Command
+	Args []string `json:"args,omitempty"`
+
+	// Number of parallel executions
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Parallelism int32 `json:"parallelism,omitempty"`
+
+	// Completion deadline in seconds
+	// +kubebuilder:validation:Minimum=1
+	DeadlineSeconds *int64 `json:"deadlineSeconds,omitempty"`
+
+	// BackoffLimit is the number of retries before marking the Job failed
+	// +kubebuilder:validation:Minimum=0
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// PodTemplate overrides pod-level fields (volumes, service account,
+	// resources, ...) of the Job created for this template. Its first
+	// container, if any, is replaced by one built from Image/Command/Args
+	// above; any further containers are kept as they are.
+	// +optional
+	PodTemplate corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+}
+
+// HookDeletePolicy determines when an owned hook Job is garbage collected
+// +kubebuilder:validation:Enum=BeforeHookCreation;HookSucceeded;HookFailed;Never
+type HookDeletePolicy string
+
+const (
+	HookDeletePolicyBeforeHookCreation HookDeletePolicy = "BeforeHookCreation"
+	HookDeletePolicyHookSucceeded      HookDeletePolicy = "HookSucceeded"
+	HookDeletePolicyHookFailed         HookDeletePolicy = "HookFailed"
+	HookDeletePolicyNever              HookDeletePolicy = "Never"
+)
+
+// Hook defines a single pre/post install or delete action, run as its own Job
+type Hook struct {
+	// Name identifies the hook within its phase. It is used to build the
+	// hook's Job name and to break ties when ordering by Weight.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Container image to run for this hook
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// Command to execute
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+
+	// Args are appended to Command
+	Args []string `json:"args,omitempty"`
+
+	// Weight orders hooks within a phase; hooks run ascending by Weight,
+	// with ties broken by Name
+	// +kubebuilder:default=0
+	Weight int `json:"weight,omitempty"`
+
+	// Timeout bounds how long the hook Job may run before it is treated as
+	// failed. Defaults to the manager's configured pre/post hook timeout.
+	// +optional
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+
+	// DeletePolicy controls when the hook's Job is garbage collected
+	// +kubebuilder:default=HookSucceeded
+	DeletePolicy HookDeletePolicy `json:"deletePolicy,omitempty"`
+}
+
+// Hooks groups the ordered hook lists run around the main Job's lifecycle
+type Hooks struct {
+	// PreInstall hooks run, in order, before the main Job is created
+	PreInstall []Hook `json:"preInstall,omitempty"`
+
+	// PostInstall hooks run, in order, after the main Job succeeds
+	PostInstall []Hook `json:"postInstall,omitempty"`
+
+	// PreDelete hooks run, in order, before the main Job is deleted
+	PreDelete []Hook `json:"preDelete,omitempty"`
+
+	// PostDelete hooks run, in order, after the main Job is deleted
+	PostDelete []Hook `json:"postDelete,omitempty"`
 }
 
 // TaskRunnerStatus defines the observed state of TaskRunner
@@ -64,6 +197,66 @@ type TaskRunnerStatus struct {
 
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Hooks records the observed state of each hook that has run, in run
+	// order
+	Hooks []HookStatus `json:"hooks,omitempty"`
+
+	// CurrentHookPhase is the hook phase currently being executed, if any
+	CurrentHookPhase string `json:"currentHookPhase,omitempty"`
+
+	// LastCompletedHook is the name of the most recently succeeded hook in
+	// CurrentHookPhase, allowing reconciliation to resume after an operator
+	// restart without re-running already-succeeded hooks
+	LastCompletedHook string `json:"lastCompletedHook,omitempty"`
+
+	// PerJobStatus records the observed state of each template's Job,
+	// keyed by template name
+	PerJobStatus map[string]JobStatus `json:"perJobStatus,omitempty"`
+}
+
+// JobStatus records the observed state of a single template's Job
+type JobStatus struct {
+	// Phase of this template's Job
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+	Phase string `json:"phase,omitempty"`
+
+	// Active is the number of currently running pods
+	Active int32 `json:"active,omitempty"`
+
+	// Succeeded is the number of successful pod completions
+	Succeeded int32 `json:"succeeded,omitempty"`
+
+	// Failed is the number of failed pods
+	Failed int32 `json:"failed,omitempty"`
+
+	// StartTime is when the Job controller started processing the Job
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the Job completed successfully; it stays unset
+	// for a Job that failed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// HookStatus records the observed outcome of a single hook execution
+type HookStatus struct {
+	// Name of the hook
+	Name string `json:"name"`
+
+	// Phase this hook ran in (PreInstall, PostInstall, PreDelete, PostDelete)
+	Phase string `json:"phase"`
+
+	// StartTime is when the hook's Job was created
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the hook's Job finished or timed out
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Succeeded reports whether the hook's Job completed successfully
+	Succeeded bool `json:"succeeded"`
+
+	// Message gives a human-readable reason for the hook's outcome
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TaskRunnerPipelineSpec defines the desired state of TaskRunnerPipeline
+type TaskRunnerPipelineSpec struct {
+	// Tasks are the TaskRunners this pipeline composes, forming a DAG via
+	// each task's RunAfter
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Tasks []PipelineTask `json:"tasks"`
+
+	// Results are named outputs of the pipeline, substituted from a task's
+	// published results once that task has succeeded
+	// +optional
+	Results []PipelineResult `json:"results,omitempty"`
+}
+
+// PipelineTask is one node in the pipeline's DAG
+type PipelineTask struct {
+	// Name identifies the task within the pipeline and is used to build its
+	// child TaskRunner name and to reference its results
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// TaskRunnerSpec is run, inline, as this task's child TaskRunner.
+	// Command and Args may reference other tasks' published results via
+	// $(tasks.<name>.results.<key>)
+	// +kubebuilder:validation:Required
+	TaskRunnerSpec TaskRunnerSpec `json:"taskRunnerSpec"`
+
+	// Results names the keys this task publishes, read from its child
+	// TaskRunner's termination message once it succeeds
+	// +optional
+	Results []string `json:"results,omitempty"`
+
+	// RunAfter lists task Names that must complete before this task starts
+	// +optional
+	RunAfter []string `json:"runAfter,omitempty"`
+
+	// When gates whether this task runs at all; if any expression
+	// evaluates false, the task is marked Skipped and does not create a
+	// child TaskRunner
+	// +optional
+	When []WhenExpression `json:"when,omitempty"`
+
+	// Retries is the number of times to recreate this task's child
+	// TaskRunner after it fails before giving up
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	Retries int `json:"retries,omitempty"`
+}
+
+// WhenOperator is the comparison used to evaluate a WhenExpression
+// +kubebuilder:validation:Enum=In;NotIn
+type WhenOperator string
+
+const (
+	WhenOperatorIn    WhenOperator = "In"
+	WhenOperatorNotIn WhenOperator = "NotIn"
+)
+
+// WhenExpression gates a PipelineTask on a single input/values comparison.
+// Input may reference another task's result via $(tasks.<name>.results.<key>)
+type WhenExpression struct {
+	// Input is the value to compare, after result substitution
+	// +kubebuilder:validation:Required
+	Input string `json:"input"`
+
+	// Operator is the comparison to apply between Input and Values
+	// +kubebuilder:validation:Required
+	Operator WhenOperator `json:"operator"`
+
+	// Values the Input is compared against
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Values []string `json:"values"`
+}
+
+// PipelineResult is a named pipeline-level output sourced from a task result
+type PipelineResult struct {
+	// Name of the pipeline result
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Value is typically a single $(tasks.<name>.results.<key>) reference
+	// +kubebuilder:validation:Required
+	Value string `json:"value"`
+}
+
+// TaskRunnerPipelineStatus defines the observed state of TaskRunnerPipeline
+type TaskRunnerPipelineStatus struct {
+	// Phase is the overall state of the pipeline
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;PartiallySucceeded
+	Phase string `json:"phase,omitempty"`
+
+	// ChildReferences tracks each task's child TaskRunner and observed phase
+	ChildReferences []ChildReference `json:"childReferences,omitempty"`
+
+	// Results holds the resolved values of Spec.Results once available
+	Results map[string]string `json:"results,omitempty"`
+
+	// Conditions represent the latest available observations
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ChildReference links a pipeline task to its child TaskRunner
+type ChildReference struct {
+	// Name of the task in Spec.Tasks
+	Name string `json:"name"`
+
+	// TaskRunnerName is the created child TaskRunner's name, empty if the
+	// task was Skipped
+	TaskRunnerName string `json:"taskRunnerName,omitempty"`
+
+	// Phase of the task: Pending, Running, Succeeded, Failed, or Skipped
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Skipped
+	Phase string `json:"phase,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// TaskRunnerPipeline is the Schema for the taskrunnerpipelines API
+type TaskRunnerPipeline struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of TaskRunnerPipeline
+	// +required
+	Spec TaskRunnerPipelineSpec `json:"spec"`
+
+	// status defines the observed state of TaskRunnerPipeline
+	// +optional
+	Status TaskRunnerPipelineStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// TaskRunnerPipelineList contains a list of TaskRunnerPipeline
+type TaskRunnerPipelineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TaskRunnerPipeline `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TaskRunnerPipeline{}, &TaskRunnerPipelineList{})
+}
@@ -0,0 +1,337 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	batchv1 "github.com/example/taskrunner-operator/api/v1"
+)
+
+// CronTaskRunnerReconciler reconciles a CronTaskRunner object
+type CronTaskRunnerReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// cronParser accepts the standard five-field cron format
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// +kubebuilder:rbac:groups=batch.example.com,resources=crontaskrunners,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch.example.com,resources=crontaskrunners/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch.example.com,resources=taskrunners,verbs=get;list;watch;create;update;patch;delete
+
+func (r *CronTaskRunnerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("crontaskrunner", req.NamespacedName)
+
+	cronTaskRunner := &batchv1.CronTaskRunner{}
+	if err := r.Get(ctx, req.NamespacedName, cronTaskRunner); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("CronTaskRunner resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get CronTaskRunner")
+		return ctrl.Result{}, err
+	}
+
+	schedule, err := cronParser.Parse(cronTaskRunner.Spec.Schedule)
+	if err != nil {
+		log.Error(err, "Invalid schedule, not requeueing", "schedule", cronTaskRunner.Spec.Schedule)
+		meta.SetStatusCondition(&cronTaskRunner.Status.Conditions, metav1.Condition{
+			Type:               "Ready",
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidSchedule",
+			Message:            err.Error(),
+			LastTransitionTime: metav1.NewTime(time.Now()),
+		})
+		return ctrl.Result{}, r.Status().Update(ctx, cronTaskRunner)
+	}
+
+	loc, err := cronLocation(cronTaskRunner.Spec.TimeZone)
+	if err != nil {
+		log.Error(err, "Invalid timeZone, not requeueing", "timeZone", *cronTaskRunner.Spec.TimeZone)
+		return ctrl.Result{}, nil
+	}
+
+	owned, err := r.listOwnedTaskRunners(ctx, cronTaskRunner)
+	if err != nil {
+		log.Error(err, "Failed to list owned TaskRunners")
+		return ctrl.Result{}, err
+	}
+
+	r.updateActiveAndLastSuccessful(cronTaskRunner, owned)
+
+	now := time.Now().In(loc)
+	lastMissed, nextRun, err := nextSchedule(cronTaskRunner, schedule, now, loc)
+	if err != nil {
+		log.Error(err, "cannot determine next scheduled run, check for clock skew or a too-short startingDeadlineSeconds", "schedule", cronTaskRunner.Spec.Schedule)
+		return ctrl.Result{}, nil
+	}
+
+	if cronTaskRunner.Spec.Suspend != nil && *cronTaskRunner.Spec.Suspend {
+		log.Info("CronTaskRunner is suspended, skipping firing")
+	} else if !lastMissed.IsZero() {
+		if requeue, err := r.fire(ctx, cronTaskRunner, lastMissed, owned); err != nil {
+			return ctrl.Result{}, err
+		} else if !requeue {
+			return ctrl.Result{}, r.Status().Update(ctx, cronTaskRunner)
+		}
+	}
+
+	if err := r.garbageCollectHistory(ctx, cronTaskRunner, owned); err != nil {
+		log.Error(err, "Failed to garbage collect TaskRunner history")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.Status().Update(ctx, cronTaskRunner); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: nextRun.Sub(now)}, nil
+}
+
+// maxMissedSchedules bounds how many times nextSchedule will step the cron
+// schedule forward looking for a missed run, guarding against a runaway loop
+// from clock skew or a schedule/deadline combination that can never catch up.
+const maxMissedSchedules = 100
+
+// nextSchedule returns the most recent scheduled time at or before now that
+// hasn't fired yet (the zero Time if none is due), and the following
+// scheduled time after now to requeue for. Firings older than
+// Spec.StartingDeadlineSeconds are never returned, bounding backfill after
+// controller downtime.
+func nextSchedule(cronTaskRunner *batchv1.CronTaskRunner, schedule cron.Schedule, now time.Time, loc *time.Location) (lastMissed, next time.Time, err error) {
+	earliestTime := cronTaskRunner.CreationTimestamp.Time.In(loc)
+	if cronTaskRunner.Status.LastScheduleTime != nil {
+		earliestTime = cronTaskRunner.Status.LastScheduleTime.Time.In(loc)
+	}
+	if deadline := cronTaskRunner.Spec.StartingDeadlineSeconds; deadline != nil {
+		if schedulingDeadline := now.Add(-time.Duration(*deadline) * time.Second); schedulingDeadline.After(earliestTime) {
+			earliestTime = schedulingDeadline
+		}
+	}
+
+	if earliestTime.After(now) {
+		return time.Time{}, schedule.Next(now), nil
+	}
+
+	starts := 0
+	for t := schedule.Next(earliestTime); !t.After(now); t = schedule.Next(t) {
+		lastMissed = t
+		starts++
+		if starts > maxMissedSchedules {
+			return time.Time{}, time.Time{}, fmt.Errorf("too many missed start times (> %d)", maxMissedSchedules)
+		}
+	}
+	return lastMissed, schedule.Next(now), nil
+}
+
+// cronLocation resolves the Spec.TimeZone, defaulting to UTC when unset.
+func cronLocation(timeZone *string) (*time.Location, error) {
+	if timeZone == nil || *timeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(*timeZone)
+}
+
+// listOwnedTaskRunners returns every TaskRunner owned by this CronTaskRunner
+func (r *CronTaskRunnerReconciler) listOwnedTaskRunners(ctx context.Context, cronTaskRunner *batchv1.CronTaskRunner) ([]batchv1.TaskRunner, error) {
+	list := &batchv1.TaskRunnerList{}
+	if err := r.List(ctx, list, client.InNamespace(cronTaskRunner.Namespace), client.MatchingLabels{
+		"batch.example.com/cron-task-runner": cronTaskRunner.Name,
+	}); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// updateActiveAndLastSuccessful recomputes Status.Active and
+// Status.LastSuccessfulTime from the owned TaskRunners' current phases.
+func (r *CronTaskRunnerReconciler) updateActiveAndLastSuccessful(cronTaskRunner *batchv1.CronTaskRunner, owned []batchv1.TaskRunner) {
+	active := make([]corev1.ObjectReference, 0, len(owned))
+	var lastSuccessful *metav1.Time
+
+	for i := range owned {
+		tr := &owned[i]
+		if !isTerminalTaskRunnerPhase(tr.Status.Phase) {
+			active = append(active, reference(tr))
+			continue
+		}
+		if tr.Status.Phase == TaskPhaseSucceeded {
+			completed := latestCompletionTime(tr)
+			if completed != nil && (lastSuccessful == nil || completed.After(lastSuccessful.Time)) {
+				lastSuccessful = completed
+			}
+		}
+	}
+
+	cronTaskRunner.Status.Active = active
+	if lastSuccessful != nil {
+		cronTaskRunner.Status.LastSuccessfulTime = lastSuccessful
+	}
+}
+
+// latestCompletionTime returns the most recent CompletionTime across a
+// succeeded TaskRunner's per-template jobs, falling back to nil.
+func latestCompletionTime(tr *batchv1.TaskRunner) *metav1.Time {
+	var latest *metav1.Time
+	for _, status := range tr.Status.PerJobStatus {
+		if status.CompletionTime != nil && (latest == nil || status.CompletionTime.After(latest.Time)) {
+			latest = status.CompletionTime
+		}
+	}
+	return latest
+}
+
+func isTerminalTaskRunnerPhase(phase string) bool {
+	return phase == TaskPhaseSucceeded || phase == TaskPhaseFailed
+}
+
+// reference builds a corev1.ObjectReference to a TaskRunner
+func reference(tr *batchv1.TaskRunner) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: "batch.example.com/v1",
+		Kind:       "TaskRunner",
+		Name:       tr.Name,
+		Namespace:  tr.Namespace,
+		UID:        tr.UID,
+	}
+}
+
+// cronChildName names a firing's child TaskRunner after the
+// CronTaskRunner and the scheduled time it fired for
+func cronChildName(cronTaskRunnerName string, scheduledTime time.Time) string {
+	return fmt.Sprintf("%s-%d", cronTaskRunnerName, scheduledTime.Unix())
+}
+
+// fire enforces ConcurrencyPolicy and creates the child TaskRunner for
+// nextFiring, which nextSchedule has already confirmed is due and within
+// Spec.StartingDeadlineSeconds. It returns true on success and when a firing
+// is skipped under Forbid, and false only together with an error; it does
+// not persist status itself.
+func (r *CronTaskRunnerReconciler) fire(ctx context.Context, cronTaskRunner *batchv1.CronTaskRunner, nextFiring time.Time, owned []batchv1.TaskRunner) (bool, error) {
+	log := r.Log.WithValues("crontaskrunner", cronTaskRunner.Name, "namespace", cronTaskRunner.Namespace)
+
+	active := activeTaskRunners(owned)
+	switch cronTaskRunner.Spec.ConcurrencyPolicy {
+	case batchv1.ConcurrencyPolicyForbid:
+		if len(active) > 0 {
+			log.Info("Previous TaskRunner still active, skipping firing due to Forbid policy")
+			return true, nil
+		}
+	case batchv1.ConcurrencyPolicyReplace:
+		for i := range active {
+			if err := r.Delete(ctx, &active[i]); err != nil && !apierrors.IsNotFound(err) {
+				return false, err
+			}
+		}
+	}
+
+	taskRunner := &batchv1.TaskRunner{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cronChildName(cronTaskRunner.Name, nextFiring),
+			Namespace: cronTaskRunner.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":             "taskrunner",
+				"app.kubernetes.io/instance":         cronChildName(cronTaskRunner.Name, nextFiring),
+				"app.kubernetes.io/created-by":       "crontaskrunner-controller",
+				"batch.example.com/cron-task-runner": cronTaskRunner.Name,
+			},
+		},
+		Spec: *cronTaskRunner.Spec.JobTemplate.DeepCopy(),
+	}
+	if err := controllerutil.SetControllerReference(cronTaskRunner, taskRunner, r.Scheme); err != nil {
+		return false, err
+	}
+	if err := r.Create(ctx, taskRunner); err != nil && !apierrors.IsAlreadyExists(err) {
+		return false, err
+	}
+
+	scheduleTime := metav1.NewTime(nextFiring)
+	cronTaskRunner.Status.LastScheduleTime = &scheduleTime
+	log.Info("Created TaskRunner for firing", "scheduledTime", nextFiring, "taskRunner", taskRunner.Name)
+	return true, nil
+}
+
+// activeTaskRunners filters owned to those not yet in a terminal phase
+func activeTaskRunners(owned []batchv1.TaskRunner) []batchv1.TaskRunner {
+	active := make([]batchv1.TaskRunner, 0, len(owned))
+	for _, tr := range owned {
+		if !isTerminalTaskRunnerPhase(tr.Status.Phase) {
+			active = append(active, tr)
+		}
+	}
+	return active
+}
+
+// garbageCollectHistory deletes terminal owned TaskRunners, oldest first,
+// down to Spec.SuccessfulHistoryLimit and Spec.FailedHistoryLimit.
+func (r *CronTaskRunnerReconciler) garbageCollectHistory(ctx context.Context, cronTaskRunner *batchv1.CronTaskRunner, owned []batchv1.TaskRunner) error {
+	if err := r.trimHistory(ctx, owned, TaskPhaseSucceeded, cronTaskRunner.Spec.SuccessfulHistoryLimit); err != nil {
+		return err
+	}
+	return r.trimHistory(ctx, owned, TaskPhaseFailed, cronTaskRunner.Spec.FailedHistoryLimit)
+}
+
+func (r *CronTaskRunnerReconciler) trimHistory(ctx context.Context, owned []batchv1.TaskRunner, phase string, limit *int32) error {
+	if limit == nil {
+		return nil
+	}
+
+	var matching []batchv1.TaskRunner
+	for _, tr := range owned {
+		if tr.Status.Phase == phase {
+			matching = append(matching, tr)
+		}
+	}
+	if int32(len(matching)) <= *limit {
+		return nil
+	}
+
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].CreationTimestamp.Before(&matching[j].CreationTimestamp)
+	})
+
+	for _, tr := range matching[:int32(len(matching))-*limit] {
+		if err := r.Delete(ctx, &tr); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *CronTaskRunnerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&batchv1.CronTaskRunner{}).
+		Owns(&batchv1.TaskRunner{}).
+		Complete(r)
+}
@@ -18,6 +18,8 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -61,11 +63,47 @@ const (
 
 	// Finalizer
 	TaskRunnerFinalizer = "batch.example.com/finalizer"
+
+	// Hook phases
+	HookPhasePreInstall  = "PreInstall"
+	HookPhasePostInstall = "PostInstall"
+	HookPhasePreDelete   = "PreDelete"
+	HookPhasePostDelete  = "PostDelete"
+)
+
+// Default hook timeouts, applied to any Hook that does not set its own
+// Timeout. Exported as vars rather than consts so a manager setup package can
+// override them before starting the reconciler. No such package exists in
+// this tree yet (there is no cmd/main.go), so --pre-hook-timeout and
+// --post-hook-timeout flags remain unimplemented until one is added; these
+// vars are what it would set.
+var (
+	DefaultPreHookTimeout  = 60 * time.Second
+	DefaultPostHookTimeout = 600 * time.Second
 )
 
+// hookPollInterval controls how often an in-flight hook Job is polled
+const hookPollInterval = 10 * time.Second
+
+// ReservedManagerName is the ManagedBy value this controller reconciles.
+// TaskRunners with any other ManagedBy value are left for an external
+// controller to admit and run. Exported as a var rather than a const so a
+// manager setup package can override it before starting the reconciler. No
+// such package exists in this tree yet (there is no cmd/main.go), so a
+// --reserved-manager-name flag remains unimplemented until one is added;
+// this var is what it would set.
+var ReservedManagerName = "batch.example.com/taskrunner-controller"
+
+// isManagedByThisController reports whether ManagedBy is unset or points at
+// this controller's reserved name.
+func isManagedByThisController(taskRunner *batchv1.TaskRunner) bool {
+	return taskRunner.Spec.ManagedBy == nil || *taskRunner.Spec.ManagedBy == ReservedManagerName
+}
+
 // +kubebuilder:rbac:groups=batch.example.com,resources=taskrunners,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=batch.example.com,resources=taskrunners/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=batch.example.com,resources=taskrunners/finalizers,verbs=update
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 
 func (r *TaskRunnerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("taskrunner", req.NamespacedName)
@@ -99,10 +137,40 @@ func (r *TaskRunnerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return r.reconcileDelete(ctx, taskRunner)
 	}
 
+	// Defer to an external controller when ManagedBy points elsewhere: don't
+	// create Jobs or add our finalizer, just reflect the delegation in status.
+	if !isManagedByThisController(taskRunner) {
+		return r.reconcileDelegated(ctx, taskRunner)
+	}
+
 	// Normal reconciliation
 	return r.reconcileNormal(ctx, taskRunner)
 }
 
+func (r *TaskRunnerReconciler) reconcileDelegated(ctx context.Context, taskRunner *batchv1.TaskRunner) (ctrl.Result, error) {
+	meta.SetStatusCondition(&taskRunner.Status.Conditions, metav1.Condition{
+		Type:               "DelegatedToExternalController",
+		Status:             metav1.ConditionTrue,
+		Reason:             "ManagedByExternalController",
+		Message:            fmt.Sprintf("managed by %q, not %q", *taskRunner.Spec.ManagedBy, ReservedManagerName),
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	})
+
+	// The external controller owns Job creation, but our Owns(&kbatch.Job{})
+	// watch still fires on its Job's status changes, so reflect them here.
+	jobName := templateJobName(taskRunner.Name, defaultTemplateName)
+	job := &kbatch.Job{}
+	if err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: taskRunner.Namespace}, job); err == nil {
+		taskRunner.Status.Active = job.Status.Active
+		taskRunner.Status.Succeeded = job.Status.Succeeded
+		taskRunner.Status.Failed = job.Status.Failed
+	} else if !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, r.Status().Update(ctx, taskRunner)
+}
+
 func (r *TaskRunnerReconciler) reconcileNormal(ctx context.Context, taskRunner *batchv1.TaskRunner) (ctrl.Result, error) {
 	log := r.Log.WithValues("taskrunner", taskRunner.Name, "namespace", taskRunner.Namespace)
 
@@ -112,23 +180,25 @@ func (r *TaskRunnerReconciler) reconcileNormal(ctx context.Context, taskRunner *
 		return ctrl.Result{}, r.Update(ctx, taskRunner)
 	}
 
-	// Create or get existing job
-	job, err := r.createOrUpdateJob(ctx, taskRunner)
-	if err != nil {
-		log.Error(err, "Failed to create or update job")
-		return ctrl.Result{}, err
+	if done, result, err := r.runHookPhase(ctx, taskRunner, HookPhasePreInstall, specHooks(taskRunner).PreInstall); !done {
+		return result, err
 	}
 
-	// Update status based on job state
-	err = r.updateStatus(ctx, taskRunner, job)
-	if err != nil {
-		log.Error(err, "Failed to update status")
+	// Create/advance the template Jobs in Sequence and update status from them
+	if err := r.reconcileJobs(ctx, taskRunner); err != nil {
+		log.Error(err, "Failed to reconcile jobs")
 		return ctrl.Result{}, err
 	}
 
 	// Determine requeue strategy based on phase
 	switch taskRunner.Status.Phase {
-	case TaskPhaseSucceeded, TaskPhaseFailed:
+	case TaskPhaseSucceeded:
+		if done, result, err := r.runHookPhase(ctx, taskRunner, HookPhasePostInstall, specHooks(taskRunner).PostInstall); !done {
+			return result, err
+		}
+		// Requeue less frequently for completed tasks
+		return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+	case TaskPhaseFailed:
 		// Requeue less frequently for completed tasks
 		return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
 	default:
@@ -137,85 +207,454 @@ func (r *TaskRunnerReconciler) reconcileNormal(ctx context.Context, taskRunner *
 	}
 }
 
-func (r *TaskRunnerReconciler) createOrUpdateJob(ctx context.Context, taskRunner *batchv1.TaskRunner) (*kbatch.Job, error) {
-	jobName := taskRunner.Name + "-job"
+// specHooks returns taskRunner.Spec.Hooks, or an empty Hooks if unset, so
+// callers can range over its fields without nil checks.
+func specHooks(taskRunner *batchv1.TaskRunner) *batchv1.Hooks {
+	if taskRunner.Spec.Hooks == nil {
+		return &batchv1.Hooks{}
+	}
+	return taskRunner.Spec.Hooks
+}
+
+// sortedHooks returns hooks ordered ascending by Weight, breaking ties by Name
+func sortedHooks(hooks []batchv1.Hook) []batchv1.Hook {
+	sorted := make([]batchv1.Hook, len(hooks))
+	copy(sorted, hooks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Weight != sorted[j].Weight {
+			return sorted[i].Weight < sorted[j].Weight
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// hookJobName returns the owned Job name for a hook within a phase
+func hookJobName(taskRunnerName, phase, hookName string) string {
+	return fmt.Sprintf("%s-%s-%s", taskRunnerName, phase, hookName)
+}
+
+// hookTimeout returns the hook's own Timeout if set, otherwise the phase's
+// default (pre-install/pre-delete vs. post-install/post-delete).
+func hookTimeout(hook batchv1.Hook, phase string) time.Duration {
+	if hook.Timeout.Duration > 0 {
+		return hook.Timeout.Duration
+	}
+	if phase == HookPhasePreInstall || phase == HookPhasePreDelete {
+		return DefaultPreHookTimeout
+	}
+	return DefaultPostHookTimeout
+}
+
+// runHookPhase runs hooks in order, resuming after LastCompletedHook if the
+// phase was already in progress. It returns done=true once every hook in the
+// phase has succeeded (or there were none to run); otherwise the caller
+// should return the accompanying result/error from Reconcile directly.
+func (r *TaskRunnerReconciler) runHookPhase(ctx context.Context, taskRunner *batchv1.TaskRunner, phase string, hooks []batchv1.Hook) (bool, ctrl.Result, error) {
+	if len(hooks) == 0 {
+		return true, ctrl.Result{}, nil
+	}
+
+	log := r.Log.WithValues("taskrunner", taskRunner.Name, "namespace", taskRunner.Namespace, "hookPhase", phase)
+
+	resuming := taskRunner.Status.CurrentHookPhase == phase
+	for _, hook := range sortedHooks(hooks) {
+		if resuming && taskRunner.Status.LastCompletedHook != "" {
+			if hook.Name == taskRunner.Status.LastCompletedHook {
+				resuming = false
+			}
+			continue
+		}
+
+		if recorded := hookStatus(taskRunner, phase, hook.Name); recorded != nil {
+			if recorded.Succeeded {
+				// Already ran to success in an earlier reconcile (CurrentHookPhase
+				// is cleared once a phase finishes, so resuming alone can't tell
+				// us this); move on to the next hook.
+				continue
+			}
+			// Already recorded as a terminal failure: the hook Job is retained
+			// (DeletePolicy defaults to HookSucceeded, not HookFailed) so it would
+			// otherwise be re-read and re-appended to Status.Hooks on every
+			// reconcile. Stop here without growing status further.
+			return false, ctrl.Result{}, nil
+		}
+
+		succeeded, message, startTime, err := r.reconcileHook(ctx, taskRunner, phase, hook)
+		if err != nil {
+			log.Error(err, "Failed to reconcile hook", "hook", hook.Name)
+			return false, ctrl.Result{}, err
+		}
+		if message == hookPendingMessage {
+			taskRunner.Status.CurrentHookPhase = phase
+			if err := r.Status().Update(ctx, taskRunner); err != nil {
+				return false, ctrl.Result{}, err
+			}
+			return false, ctrl.Result{RequeueAfter: hookPollInterval}, nil
+		}
+
+		now := metav1.NewTime(time.Now())
+		taskRunner.Status.Hooks = append(taskRunner.Status.Hooks, batchv1.HookStatus{
+			Name:           hook.Name,
+			Phase:          phase,
+			StartTime:      startTime,
+			CompletionTime: &now,
+			Succeeded:      succeeded,
+			Message:        message,
+		})
+
+		if err := r.garbageCollectHookJob(ctx, taskRunner, phase, hook, succeeded); err != nil {
+			return false, ctrl.Result{}, err
+		}
+
+		if !succeeded {
+			taskRunner.Status.Phase = TaskPhaseFailed
+			taskRunner.Status.CurrentHookPhase = phase
+			meta.SetStatusCondition(&taskRunner.Status.Conditions, metav1.Condition{
+				Type:               "Ready",
+				Status:             metav1.ConditionFalse,
+				Reason:             "HookFailed",
+				Message:            fmt.Sprintf("hook %q in phase %s failed: %s", hook.Name, phase, message),
+				LastTransitionTime: now,
+			})
+			return false, ctrl.Result{}, r.Status().Update(ctx, taskRunner)
+		}
+
+		taskRunner.Status.CurrentHookPhase = phase
+		taskRunner.Status.LastCompletedHook = hook.Name
+		if err := r.Status().Update(ctx, taskRunner); err != nil {
+			return false, ctrl.Result{}, err
+		}
+	}
+
+	taskRunner.Status.CurrentHookPhase = ""
+	taskRunner.Status.LastCompletedHook = ""
+	return true, ctrl.Result{}, r.Status().Update(ctx, taskRunner)
+}
+
+// hookPendingMessage is returned by reconcileHook to signal that the hook
+// Job was just created or is still running and must be polled again
+const hookPendingMessage = "pending"
+
+// hookStatus returns the previously recorded HookStatus for a hook within a
+// phase, or nil if it hasn't run to completion yet.
+func hookStatus(taskRunner *batchv1.TaskRunner, phase, hookName string) *batchv1.HookStatus {
+	for i := range taskRunner.Status.Hooks {
+		status := &taskRunner.Status.Hooks[i]
+		if status.Phase == phase && status.Name == hookName {
+			return status
+		}
+	}
+	return nil
+}
+
+// reconcileHook creates the hook's Job if needed and reports whether it has
+// reached a terminal state. message is hookPendingMessage while the hook Job
+// is still in flight. startTime is the Job's creation time, set once the Job
+// exists.
+func (r *TaskRunnerReconciler) reconcileHook(ctx context.Context, taskRunner *batchv1.TaskRunner, phase string, hook batchv1.Hook) (succeeded bool, message string, startTime *metav1.Time, err error) {
+	jobName := hookJobName(taskRunner.Name, phase, hook.Name)
+
+	job := &kbatch.Job{}
+	err = r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: taskRunner.Namespace}, job)
+	if apierrors.IsNotFound(err) {
+		job = &kbatch.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      jobName,
+				Namespace: taskRunner.Namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/name":       "taskrunner",
+					"app.kubernetes.io/instance":   taskRunner.Name,
+					"app.kubernetes.io/created-by": "taskrunner-controller",
+					"batch.example.com/hook-phase": phase,
+					"batch.example.com/hook-name":  hook.Name,
+				},
+			},
+			Spec: kbatch.JobSpec{
+				ActiveDeadlineSeconds: ptrInt64(int64(hookTimeout(hook, phase).Seconds())),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						RestartPolicy: corev1.RestartPolicyNever,
+						Containers: []corev1.Container{
+							{
+								Name:    "hook",
+								Image:   hook.Image,
+								Command: hook.Command,
+								Args:    hook.Args,
+							},
+						},
+					},
+				},
+			},
+		}
+		if err := controllerutil.SetControllerReference(taskRunner, job, r.Scheme); err != nil {
+			return false, "", nil, err
+		}
+		if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+			return false, "", nil, err
+		}
+		now := metav1.NewTime(time.Now())
+		return false, hookPendingMessage, &now, nil
+	} else if err != nil {
+		return false, "", nil, err
+	}
+
+	startTime = &job.CreationTimestamp
+	switch {
+	case job.Status.Succeeded > 0:
+		return true, "hook job succeeded", startTime, nil
+	case job.Status.Failed > 0:
+		return false, "hook job failed", startTime, nil
+	default:
+		return false, hookPendingMessage, startTime, nil
+	}
+}
+
+// garbageCollectHookJob deletes the hook's Job when its DeletePolicy calls
+// for it given the hook's outcome.
+func (r *TaskRunnerReconciler) garbageCollectHookJob(ctx context.Context, taskRunner *batchv1.TaskRunner, phase string, hook batchv1.Hook, succeeded bool) error {
+	shouldDelete := false
+	switch hook.DeletePolicy {
+	case batchv1.HookDeletePolicyHookSucceeded:
+		shouldDelete = succeeded
+	case batchv1.HookDeletePolicyHookFailed:
+		shouldDelete = !succeeded
+	case batchv1.HookDeletePolicyNever, batchv1.HookDeletePolicyBeforeHookCreation:
+		// BeforeHookCreation deletes nothing here, and reconcileHook does not
+		// delete an earlier hook Job either: an existing Job of the same name
+		// is reused
+		shouldDelete = false
+	default:
+		shouldDelete = succeeded
+	}
+	if !shouldDelete {
+		return nil
+	}
+
+	job := &kbatch.Job{}
+	jobName := hookJobName(taskRunner.Name, phase, hook.Name)
+	err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: taskRunner.Namespace}, job)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	background := metav1.DeletePropagationBackground
+	err = r.Delete(ctx, job, &client.DeleteOptions{PropagationPolicy: &background})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func ptrInt64(v int64) *int64 {
+	return &v
+}
+
+// defaultTemplateName is the template synthesized from the deprecated
+// top-level Image/Command/Parallelism/DeadlineSeconds fields
+const defaultTemplateName = "default"
+
+// templateJobName returns the owned Job name for a named template
+func templateJobName(taskRunnerName, templateName string) string {
+	return taskRunnerName + "-" + templateName
+}
+
+// effectiveTemplates returns the Templates/Sequence to run, synthesizing a
+// "default" template from the deprecated top-level fields when Templates is
+// unset so single-job TaskRunners keep working unchanged.
+func effectiveTemplates(taskRunner *batchv1.TaskRunner) (map[string]batchv1.JobTemplate, []string) {
+	if len(taskRunner.Spec.Templates) == 0 {
+		return map[string]batchv1.JobTemplate{
+			defaultTemplateName: {
+				Image:           taskRunner.Spec.Image,
+				Command:         taskRunner.Spec.Command,
+				Parallelism:     taskRunner.Spec.Parallelism,
+				DeadlineSeconds: taskRunner.Spec.DeadlineSeconds,
+			},
+		}, []string{defaultTemplateName}
+	}
+
+	sequence := taskRunner.Spec.Sequence
+	if len(sequence) == 0 {
+		for name := range taskRunner.Spec.Templates {
+			sequence = append(sequence, name)
+		}
+		sort.Strings(sequence)
+	}
+	return taskRunner.Spec.Templates, sequence
+}
+
+// reconcileJobs materializes the Job for each template in Sequence,
+// advancing to template N+1 only once template N's Job has succeeded, and
+// recomputes the TaskRunner's overall status from every template's Job.
+func (r *TaskRunnerReconciler) reconcileJobs(ctx context.Context, taskRunner *batchv1.TaskRunner) error {
+	templates, sequence := effectiveTemplates(taskRunner)
+	if taskRunner.Status.PerJobStatus == nil {
+		taskRunner.Status.PerJobStatus = map[string]batchv1.JobStatus{}
+	}
+
+	oldPhase := taskRunner.Status.Phase
+	anyFailed, anyActive, succeededCount := false, false, 0
+
+	for _, name := range sequence {
+		template, ok := templates[name]
+		if !ok {
+			// Sequence names a template that doesn't exist in Templates; treat
+			// this the same as a failed Job rather than silently skipping it,
+			// which would otherwise leave succeededCount permanently short of
+			// len(sequence) and the TaskRunner stuck out of Succeeded forever.
+			r.Log.Error(fmt.Errorf("template %q not found", name), "Sequence references a template that does not exist", "taskrunner", taskRunner.Name)
+			anyFailed = true
+			break
+		}
+
+		job, err := r.getOrCreateTemplateJob(ctx, taskRunner, name, template)
+		if err != nil {
+			return err
+		}
+
+		status := jobStatusFromJob(job)
+		taskRunner.Status.PerJobStatus[name] = status
+
+		switch status.Phase {
+		case TaskPhaseFailed:
+			anyFailed = true
+		case TaskPhaseRunning:
+			anyActive = true
+		case TaskPhaseSucceeded:
+			succeededCount++
+			continue
+		}
+
+		// This template's Job hasn't succeeded yet; don't create the next one
+		break
+	}
+
+	var totalActive, totalSucceeded, totalFailed int32
+	for _, status := range taskRunner.Status.PerJobStatus {
+		totalActive += status.Active
+		totalSucceeded += status.Succeeded
+		totalFailed += status.Failed
+	}
+	taskRunner.Status.Active = totalActive
+	taskRunner.Status.Succeeded = totalSucceeded
+	taskRunner.Status.Failed = totalFailed
+
+	switch {
+	case anyFailed:
+		taskRunner.Status.Phase = TaskPhaseFailed
+	case succeededCount == len(sequence):
+		taskRunner.Status.Phase = TaskPhaseSucceeded
+	case anyActive:
+		taskRunner.Status.Phase = TaskPhaseRunning
+	default:
+		taskRunner.Status.Phase = TaskPhasePending
+	}
+
+	if oldPhase != taskRunner.Status.Phase {
+		meta.SetStatusCondition(&taskRunner.Status.Conditions, r.buildConditionForPhase(taskRunner.Status.Phase))
+	}
+
+	return r.Status().Update(ctx, taskRunner)
+}
+
+// getOrCreateTemplateJob returns the existing Job for a template, creating
+// it if it doesn't exist yet.
+func (r *TaskRunnerReconciler) getOrCreateTemplateJob(ctx context.Context, taskRunner *batchv1.TaskRunner, name string, template batchv1.JobTemplate) (*kbatch.Job, error) {
+	jobName := templateJobName(taskRunner.Name, name)
 
-	// Check if job already exists
 	existingJob := &kbatch.Job{}
 	err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: taskRunner.Namespace}, existingJob)
 	if err == nil {
-		// Job exists, return it
 		return existingJob, nil
 	} else if !apierrors.IsNotFound(err) {
-		// Real error occurred
 		return nil, err
 	}
 
-	// Create new job
+	podSpec := template.PodTemplate.Spec.DeepCopy()
+	if podSpec.RestartPolicy == "" {
+		podSpec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	container := corev1.Container{
+		Name:                     "task",
+		Image:                    template.Image,
+		Command:                  template.Command,
+		Args:                     template.Args,
+		TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+	}
+	if len(podSpec.Containers) > 0 {
+		podSpec.Containers[0] = container
+	} else {
+		podSpec.Containers = []corev1.Container{container}
+	}
+
+	labels := map[string]string{
+		"app.kubernetes.io/name":       "taskrunner",
+		"app.kubernetes.io/instance":   taskRunner.Name,
+		"app.kubernetes.io/created-by": "taskrunner-controller",
+		"batch.example.com/template":   name,
+	}
+
+	// The Job's own ObjectMeta labels don't propagate to its pods, so readers
+	// (e.g. the TaskRunnerPipeline controller's readTaskResults) that select
+	// pods by app.kubernetes.io/instance need the label on the pod template too.
+	podObjectMeta := *template.PodTemplate.ObjectMeta.DeepCopy()
+	if podObjectMeta.Labels == nil {
+		podObjectMeta.Labels = make(map[string]string, len(labels))
+	}
+	for k, v := range labels {
+		podObjectMeta.Labels[k] = v
+	}
+
+	parallelism := template.Parallelism
 	job := &kbatch.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      jobName,
 			Namespace: taskRunner.Namespace,
-			Labels: map[string]string{
-				"app.kubernetes.io/name":       "taskrunner",
-				"app.kubernetes.io/instance":   taskRunner.Name,
-				"app.kubernetes.io/created-by": "taskrunner-controller",
-			},
+			Labels:    labels,
 		},
 		Spec: kbatch.JobSpec{
-			Parallelism:           &taskRunner.Spec.Parallelism,
-			ActiveDeadlineSeconds: taskRunner.Spec.DeadlineSeconds,
+			Parallelism:           &parallelism,
+			ActiveDeadlineSeconds: template.DeadlineSeconds,
+			BackoffLimit:          template.BackoffLimit,
 			Template: corev1.PodTemplateSpec{
-				Spec: corev1.PodSpec{
-					RestartPolicy: corev1.RestartPolicyNever,
-					Containers: []corev1.Container{
-						{
-							Name:    "task",
-							Image:   taskRunner.Spec.Image,
-							Command: taskRunner.Spec.Command,
-						},
-					},
-				},
+				ObjectMeta: podObjectMeta,
+				Spec:       *podSpec,
 			},
 		},
 	}
 
-	// Set TaskRunner as owner
 	if err := controllerutil.SetControllerReference(taskRunner, job, r.Scheme); err != nil {
 		return nil, err
 	}
 
-	// Create the job
 	err = r.Create(ctx, job)
 	return job, err
 }
 
-func (r *TaskRunnerReconciler) updateStatus(ctx context.Context, taskRunner *batchv1.TaskRunner, job *kbatch.Job) error {
-	// Update counters from job status
-	taskRunner.Status.Active = job.Status.Active
-	taskRunner.Status.Succeeded = job.Status.Succeeded
-	taskRunner.Status.Failed = job.Status.Failed
-
-	// Determine current phase
-	oldPhase := taskRunner.Status.Phase
-
-	if job.Status.Succeeded > 0 {
-		taskRunner.Status.Phase = TaskPhaseSucceeded
-	} else if job.Status.Failed > 0 {
-		taskRunner.Status.Phase = TaskPhaseFailed
-	} else if job.Status.Active > 0 {
-		taskRunner.Status.Phase = TaskPhaseRunning
-	} else {
-		taskRunner.Status.Phase = TaskPhasePending
+// jobStatusFromJob derives a template's JobStatus from its owned Job
+func jobStatusFromJob(job *kbatch.Job) batchv1.JobStatus {
+	status := batchv1.JobStatus{
+		Active:         job.Status.Active,
+		Succeeded:      job.Status.Succeeded,
+		Failed:         job.Status.Failed,
+		StartTime:      job.Status.StartTime,
+		CompletionTime: job.Status.CompletionTime,
 	}
 
-	// Update conditions when phase changes
-	if oldPhase != taskRunner.Status.Phase {
-		condition := r.buildConditionForPhase(taskRunner.Status.Phase)
-		meta.SetStatusCondition(&taskRunner.Status.Conditions, condition)
+	switch {
+	case job.Status.Succeeded > 0:
+		status.Phase = TaskPhaseSucceeded
+	case job.Status.Failed > 0:
+		status.Phase = TaskPhaseFailed
+	case job.Status.Active > 0:
+		status.Phase = TaskPhaseRunning
+	default:
+		status.Phase = TaskPhasePending
 	}
-
-	return r.Status().Update(ctx, taskRunner)
+	return status
 }
 
 func (r *TaskRunnerReconciler) buildConditionForPhase(phase string) metav1.Condition {
@@ -250,8 +689,28 @@ func (r *TaskRunnerReconciler) reconcileDelete(ctx context.Context, taskRunner *
 	log := r.Log.WithValues("taskrunner", taskRunner.Name, "namespace", taskRunner.Namespace)
 	log.Info("Handling TaskRunner deletion")
 
-	// Clean up any resources if needed
-	// For this example, Jobs will be cleaned up automatically via owner references
+	if done, result, err := r.runHookPhase(ctx, taskRunner, HookPhasePreDelete, specHooks(taskRunner).PreDelete); !done {
+		return result, err
+	}
+
+	// Delete the Job for every template before running PostDelete hooks
+	_, sequence := effectiveTemplates(taskRunner)
+	for _, name := range sequence {
+		job := &kbatch.Job{}
+		jobName := templateJobName(taskRunner.Name, name)
+		err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: taskRunner.Namespace}, job)
+		if err == nil {
+			if err := r.Delete(ctx, job); err != nil && !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+		} else if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if done, result, err := r.runHookPhase(ctx, taskRunner, HookPhasePostDelete, specHooks(taskRunner).PostDelete); !done {
+		return result, err
+	}
 
 	// Remove finalizer to allow deletion
 	controllerutil.RemoveFinalizer(taskRunner, TaskRunnerFinalizer)
@@ -262,17 +721,27 @@ func (r *TaskRunnerReconciler) reconcileRemoved(ctx context.Context, taskRunner
 	log := r.Log.WithValues("taskrunner", taskRunner.Name, "namespace", taskRunner.Namespace)
 	log.Info("TaskRunner marked for removal")
 
-	// Delete associated job
-	jobName := taskRunner.Name + "-job"
-	job := &kbatch.Job{}
-	err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: taskRunner.Namespace}, job)
-	if err == nil {
-		err = r.Delete(ctx, job)
-		if err != nil {
+	if done, result, err := r.runHookPhase(ctx, taskRunner, HookPhasePreDelete, specHooks(taskRunner).PreDelete); !done {
+		return result, err
+	}
+
+	// Delete the Job for every template
+	_, sequence := effectiveTemplates(taskRunner)
+	for _, name := range sequence {
+		job := &kbatch.Job{}
+		jobName := templateJobName(taskRunner.Name, name)
+		err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: taskRunner.Namespace}, job)
+		if err == nil {
+			if err := r.Delete(ctx, job); err != nil {
+				return ctrl.Result{}, err
+			}
+		} else if !apierrors.IsNotFound(err) {
 			return ctrl.Result{}, err
 		}
-	} else if !apierrors.IsNotFound(err) {
-		return ctrl.Result{}, err
+	}
+
+	if done, result, err := r.runHookPhase(ctx, taskRunner, HookPhasePostDelete, specHooks(taskRunner).PostDelete); !done {
+		return result, err
 	}
 
 	return ctrl.Result{RequeueAfter: time.Minute}, nil
@@ -291,7 +760,9 @@ func (r *TaskRunnerReconciler) jobPredicate() predicate.Funcs {
 			oldJob := e.ObjectOld.(*kbatch.Job)
 			newJob := e.ObjectNew.(*kbatch.Job)
 
-			// Only reconcile when job status changes meaningfully
+			// Only reconcile when job status changes meaningfully. This also
+			// covers Jobs created by an external controller for a delegated
+			// (ManagedBy) TaskRunner, so its status keeps reflecting them.
 			return oldJob.Status.Active != newJob.Status.Active ||
 				oldJob.Status.Succeeded != newJob.Status.Succeeded ||
 				oldJob.Status.Failed != newJob.Status.Failed
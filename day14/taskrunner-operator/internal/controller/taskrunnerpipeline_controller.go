@@ -0,0 +1,384 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"time"
+
+	batchv1 "github.com/example/taskrunner-operator/api/v1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// TaskRunnerPipelineReconciler reconciles a TaskRunnerPipeline object
+type TaskRunnerPipelineReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+const (
+	PipelinePhasePending            = "Pending"
+	PipelinePhaseRunning            = "Running"
+	PipelinePhaseSucceeded          = "Succeeded"
+	PipelinePhaseFailed             = "Failed"
+	PipelinePhasePartiallySucceeded = "PartiallySucceeded"
+
+	// TaskPhaseSkipped marks a PipelineTask that did not run, either because
+	// a When expression evaluated false or because a non-skipped parent failed
+	TaskPhaseSkipped = "Skipped"
+)
+
+// resultRefPattern matches $(tasks.<name>.results.<key>) references in a
+// task's Command/Args or When.Input
+var resultRefPattern = regexp.MustCompile(`\$\(tasks\.([^.]+)\.results\.([^)]+)\)`)
+
+// +kubebuilder:rbac:groups=batch.example.com,resources=taskrunnerpipelines,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch.example.com,resources=taskrunnerpipelines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch.example.com,resources=taskrunners,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+func (r *TaskRunnerPipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("taskrunnerpipeline", req.NamespacedName)
+
+	pipeline := &batchv1.TaskRunnerPipeline{}
+	if err := r.Get(ctx, req.NamespacedName, pipeline); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("TaskRunnerPipeline resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	childRefs := make(map[string]batchv1.ChildReference, len(pipeline.Status.ChildReferences))
+	for _, ref := range pipeline.Status.ChildReferences {
+		childRefs[ref.Name] = ref
+	}
+
+	// Read every already-succeeded task's results up front, in a pass of its
+	// own: Spec.Tasks is declared order, not topological order, so a task can
+	// be listed before a RunAfter parent that finished long ago. Resolving
+	// results lazily in the loop below would leave that parent's results
+	// empty while the child is being processed.
+	results := make(map[string]map[string]string, len(pipeline.Spec.Tasks))
+	for _, task := range pipeline.Spec.Tasks {
+		ref, seen := childRefs[task.Name]
+		if !seen || ref.Phase != TaskPhaseSucceeded || ref.TaskRunnerName == "" {
+			continue
+		}
+		taskResults, err := r.readTaskResults(ctx, pipeline.Namespace, ref.TaskRunnerName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		results[task.Name] = taskResults
+	}
+
+	attemptsChanged := false
+
+	for _, task := range pipeline.Spec.Tasks {
+		ref, seen := childRefs[task.Name]
+		if seen && isTerminalTaskPhase(ref.Phase) {
+			continue
+		}
+
+		if !allParentsTerminal(task.RunAfter, childRefs) {
+			childRefs[task.Name] = batchv1.ChildReference{Name: task.Name, Phase: TaskPhasePending}
+			continue
+		}
+
+		if blocked := anyNonSkippedParentFailed(task.RunAfter, childRefs); blocked {
+			childRefs[task.Name] = batchv1.ChildReference{Name: task.Name, Phase: TaskPhaseSkipped}
+			continue
+		}
+
+		if !evaluateWhen(task.When, results) {
+			childRefs[task.Name] = batchv1.ChildReference{Name: task.Name, Phase: TaskPhaseSkipped}
+			continue
+		}
+
+		newRef, retried, err := r.reconcileTask(ctx, pipeline, task, results)
+		if err != nil {
+			log.Error(err, "Failed to reconcile pipeline task", "task", task.Name)
+			return ctrl.Result{}, err
+		}
+		if retried {
+			attemptsChanged = true
+		}
+		childRefs[task.Name] = newRef
+
+		// A task that succeeded during this pass publishes its results to the
+		// tasks declared after it
+		if newRef.Phase == TaskPhaseSucceeded {
+			taskResults, err := r.readTaskResults(ctx, pipeline.Namespace, newRef.TaskRunnerName)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			results[task.Name] = taskResults
+		}
+	}
+
+	pipeline.Status.ChildReferences = pipeline.Status.ChildReferences[:0]
+	for _, task := range pipeline.Spec.Tasks {
+		pipeline.Status.ChildReferences = append(pipeline.Status.ChildReferences, childRefs[task.Name])
+	}
+
+	pipeline.Status.Results = resolvePipelineResults(pipeline.Spec.Results, results)
+	pipeline.Status.Phase = derivePipelinePhase(pipeline.Status.ChildReferences)
+
+	if attemptsChanged {
+		if err := r.Update(ctx, pipeline); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if err := r.Status().Update(ctx, pipeline); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	switch pipeline.Status.Phase {
+	case PipelinePhaseSucceeded, PipelinePhaseFailed, PipelinePhasePartiallySucceeded:
+		return ctrl.Result{}, nil
+	default:
+		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+	}
+}
+
+func isTerminalTaskPhase(phase string) bool {
+	return phase == TaskPhaseSucceeded || phase == TaskPhaseFailed || phase == TaskPhaseSkipped
+}
+
+func allParentsTerminal(runAfter []string, childRefs map[string]batchv1.ChildReference) bool {
+	for _, parent := range runAfter {
+		ref, ok := childRefs[parent]
+		if !ok || !isTerminalTaskPhase(ref.Phase) {
+			return false
+		}
+	}
+	return true
+}
+
+func anyNonSkippedParentFailed(runAfter []string, childRefs map[string]batchv1.ChildReference) bool {
+	for _, parent := range runAfter {
+		if ref := childRefs[parent]; ref.Phase == TaskPhaseFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateWhen reports whether every When expression is satisfied, after
+// substituting task results into Input
+func evaluateWhen(when []batchv1.WhenExpression, results map[string]map[string]string) bool {
+	for _, expr := range when {
+		input := substituteResults(expr.Input, results)
+		matched := false
+		for _, v := range expr.Values {
+			if v == input {
+				matched = true
+				break
+			}
+		}
+		switch expr.Operator {
+		case batchv1.WhenOperatorIn:
+			if !matched {
+				return false
+			}
+		case batchv1.WhenOperatorNotIn:
+			if matched {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// substituteResults replaces $(tasks.<name>.results.<key>) references with
+// the named task's published result, leaving unresolved references untouched
+func substituteResults(s string, results map[string]map[string]string) string {
+	return resultRefPattern.ReplaceAllStringFunc(s, func(match string) string {
+		sub := resultRefPattern.FindStringSubmatch(match)
+		if v, ok := results[sub[1]][sub[2]]; ok {
+			return v
+		}
+		return match
+	})
+}
+
+func substituteResultsSlice(values []string, results map[string]map[string]string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = substituteResults(v, results)
+	}
+	return out
+}
+
+func resolvePipelineResults(defs []batchv1.PipelineResult, results map[string]map[string]string) map[string]string {
+	if len(defs) == 0 {
+		return nil
+	}
+	resolved := make(map[string]string, len(defs))
+	for _, def := range defs {
+		resolved[def.Name] = substituteResults(def.Value, results)
+	}
+	return resolved
+}
+
+// derivePipelinePhase rolls every task's phase up into the pipeline's phase
+func derivePipelinePhase(refs []batchv1.ChildReference) string {
+	started, allTerminal, anySucceeded, anyFailed := false, true, false, false
+	for _, ref := range refs {
+		if ref.Phase != TaskPhasePending {
+			started = true
+		}
+		if !isTerminalTaskPhase(ref.Phase) {
+			allTerminal = false
+		}
+		if ref.Phase == TaskPhaseSucceeded {
+			anySucceeded = true
+		}
+		if ref.Phase == TaskPhaseFailed {
+			anyFailed = true
+		}
+	}
+
+	switch {
+	case !allTerminal && started:
+		return PipelinePhaseRunning
+	case !allTerminal:
+		return PipelinePhasePending
+	case anyFailed && anySucceeded:
+		return PipelinePhasePartiallySucceeded
+	case anyFailed:
+		return PipelinePhaseFailed
+	default:
+		return PipelinePhaseSucceeded
+	}
+}
+
+func childTaskRunnerName(pipeline *batchv1.TaskRunnerPipeline, taskName string) string {
+	return pipeline.Name + "-" + taskName
+}
+
+func attemptAnnotationKey(taskName string) string {
+	return "batch.example.com/attempt-" + taskName
+}
+
+func attemptCount(pipeline *batchv1.TaskRunnerPipeline, taskName string) int {
+	n, _ := strconv.Atoi(pipeline.Annotations[attemptAnnotationKey(taskName)])
+	return n
+}
+
+// reconcileTask creates the task's child TaskRunner if needed, retrying it
+// (by deleting and letting the next reconcile recreate it) up to Retries
+// times after a failure. retried reports whether pipeline.Annotations was
+// mutated and must be persisted with a metadata Update.
+func (r *TaskRunnerPipelineReconciler) reconcileTask(ctx context.Context, pipeline *batchv1.TaskRunnerPipeline, task batchv1.PipelineTask, results map[string]map[string]string) (batchv1.ChildReference, bool, error) {
+	name := childTaskRunnerName(pipeline, task.Name)
+
+	taskRunner := &batchv1.TaskRunner{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: pipeline.Namespace}, taskRunner)
+	if apierrors.IsNotFound(err) {
+		spec := *task.TaskRunnerSpec.DeepCopy()
+		spec.Command = substituteResultsSlice(spec.Command, results)
+		for templateName, template := range spec.Templates {
+			template.Command = substituteResultsSlice(template.Command, results)
+			template.Args = substituteResultsSlice(template.Args, results)
+			spec.Templates[templateName] = template
+		}
+
+		taskRunner = &batchv1.TaskRunner{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: pipeline.Namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/name":          "taskrunnerpipeline",
+					"app.kubernetes.io/instance":      pipeline.Name,
+					"app.kubernetes.io/created-by":    "taskrunnerpipeline-controller",
+					"batch.example.com/pipeline-task": task.Name,
+				},
+			},
+			Spec: spec,
+		}
+		if err := controllerutil.SetControllerReference(pipeline, taskRunner, r.Scheme); err != nil {
+			return batchv1.ChildReference{}, false, err
+		}
+		if err := r.Create(ctx, taskRunner); err != nil {
+			return batchv1.ChildReference{}, false, err
+		}
+		return batchv1.ChildReference{Name: task.Name, TaskRunnerName: name, Phase: TaskPhasePending}, false, nil
+	} else if err != nil {
+		return batchv1.ChildReference{}, false, err
+	}
+
+	phase := taskRunner.Status.Phase
+	if phase == "" {
+		phase = TaskPhasePending
+	}
+
+	if phase == TaskPhaseFailed && attemptCount(pipeline, task.Name) < task.Retries {
+		if err := r.Delete(ctx, taskRunner); err != nil && !apierrors.IsNotFound(err) {
+			return batchv1.ChildReference{}, false, err
+		}
+		if pipeline.Annotations == nil {
+			pipeline.Annotations = map[string]string{}
+		}
+		pipeline.Annotations[attemptAnnotationKey(task.Name)] = strconv.Itoa(attemptCount(pipeline, task.Name) + 1)
+		return batchv1.ChildReference{Name: task.Name, TaskRunnerName: name, Phase: TaskPhasePending}, true, nil
+	}
+
+	return batchv1.ChildReference{Name: task.Name, TaskRunnerName: name, Phase: phase}, false, nil
+}
+
+// readTaskResults reads the published results of a succeeded task's child
+// TaskRunner from its pod's termination message, expected to be a JSON
+// object of result key to value.
+func (r *TaskRunnerPipelineReconciler) readTaskResults(ctx context.Context, namespace, taskRunnerName string) (map[string]string, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels{"app.kubernetes.io/instance": taskRunnerName}); err != nil {
+		return nil, err
+	}
+
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Terminated == nil || cs.State.Terminated.Message == "" {
+				continue
+			}
+			results := map[string]string{}
+			if err := json.Unmarshal([]byte(cs.State.Terminated.Message), &results); err == nil {
+				return results, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (r *TaskRunnerPipelineReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&batchv1.TaskRunnerPipeline{}).
+		Owns(&batchv1.TaskRunner{}).
+		Complete(r)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	batchv1 "github.com/example/taskrunner-operator/api/v1"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var taskrunnerpipelinelog = logf.Log.WithName("taskrunnerpipeline-resource")
+
+// SetupTaskRunnerPipelineWebhookWithManager registers the webhook for TaskRunnerPipeline in the manager.
+func SetupTaskRunnerPipelineWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&batchv1.TaskRunnerPipeline{}).
+		WithValidator(&TaskRunnerPipelineCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-batch-example-com-v1-taskrunnerpipeline,mutating=false,failurePolicy=fail,sideEffects=None,groups=batch.example.com,resources=taskrunnerpipelines,verbs=create;update,versions=v1,name=vtaskrunnerpipeline-v1.kb.io,admissionReviewVersions=v1
+
+// TaskRunnerPipelineCustomValidator struct is responsible for validating the TaskRunnerPipeline resource
+// when it is created or updated, rejecting DAGs with cycles or dangling RunAfter references.
+type TaskRunnerPipelineCustomValidator struct{}
+
+var _ webhook.CustomValidator = &TaskRunnerPipelineCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a validator will be registered for the type TaskRunnerPipeline.
+func (v *TaskRunnerPipelineCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pipeline, ok := obj.(*batchv1.TaskRunnerPipeline)
+	if !ok {
+		return nil, fmt.Errorf("expected a TaskRunnerPipeline object but got %T", obj)
+	}
+	taskrunnerpipelinelog.Info("Validation for TaskRunnerPipeline upon creation", "name", pipeline.GetName())
+
+	return nil, validateTaskDAG(pipeline)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a validator will be registered for the type TaskRunnerPipeline.
+func (v *TaskRunnerPipelineCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	pipeline, ok := newObj.(*batchv1.TaskRunnerPipeline)
+	if !ok {
+		return nil, fmt.Errorf("expected a TaskRunnerPipeline object but got %T", newObj)
+	}
+	taskrunnerpipelinelog.Info("Validation for TaskRunnerPipeline upon update", "name", pipeline.GetName())
+
+	return nil, validateTaskDAG(pipeline)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a validator will be registered for the type TaskRunnerPipeline.
+func (v *TaskRunnerPipelineCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateTaskDAG checks that every RunAfter reference names an existing task and that the
+// resulting graph has no cycles, via a DFS with the standard white/gray/black coloring.
+func validateTaskDAG(pipeline *batchv1.TaskRunnerPipeline) error {
+	tasksByName := make(map[string]batchv1.PipelineTask, len(pipeline.Spec.Tasks))
+	for _, task := range pipeline.Spec.Tasks {
+		tasksByName[task.Name] = task
+	}
+
+	const (
+		white = iota // unvisited
+		gray         // on the current DFS path
+		black        // fully visited
+	)
+	color := make(map[string]int, len(tasksByName))
+
+	var fieldErrs field.ErrorList
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch color[name] {
+		case black:
+			return nil
+		case gray:
+			return fmt.Errorf("cycle detected in tasks: %v -> %s", path, name)
+		}
+
+		color[name] = gray
+		for _, parent := range tasksByName[name].RunAfter {
+			if _, ok := tasksByName[parent]; !ok {
+				fieldErrs = append(fieldErrs, field.Invalid(
+					field.NewPath("spec").Child("tasks").Key(name).Child("runAfter"),
+					parent, "references a task that does not exist"))
+				continue
+			}
+			if err := visit(parent, append(path, name)); err != nil {
+				return err
+			}
+		}
+		color[name] = black
+		return nil
+	}
+
+	for name := range tasksByName {
+		if err := visit(name, nil); err != nil {
+			fieldErrs = append(fieldErrs, field.Invalid(field.NewPath("spec").Child("tasks"), name, err.Error()))
+		}
+	}
+
+	if len(fieldErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "batch.example.com", Kind: "TaskRunnerPipeline"},
+		pipeline.Name, fieldErrs)
+}